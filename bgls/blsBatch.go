@@ -0,0 +1,132 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package bgls
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/blake2b"
+
+	. "github.com/Project-Arda/bgls/curves"
+)
+
+// batchScalarBits bounds the random per-triple scalars drawn by BatchVerify
+// at 128 bits, matching the exponent width HAE uses elsewhere in this
+// package (see hashPubKeysToExponents in blsHAE.go).
+const batchScalarBits = 128
+
+// BatchVerify checks n independent (signer, message, signature) triples in
+// a single multi-pairing. It draws a fresh random 128-bit scalar r_i per
+// triple from a CSPRNG, so an attacker who submits carefully-chosen invalid
+// triples can't make them cancel each other out the way they could if every
+// triple were weighted equally. This is unrelated to HAE or PoP, which
+// defend an aggregate against rogue keys; BatchVerify instead defends a
+// batch of otherwise-independent signatures against a malicious submitter.
+func BatchVerify(curve CurveSystem, sigs []Point1, pubkeys []Point2, msgs [][]byte) (bool, error) {
+	scalars, err := randomScalars(len(sigs))
+	if err != nil {
+		return false, err
+	}
+	return batchVerify(curve, sigs, pubkeys, msgs, scalars)
+}
+
+// SecureBatchVerify is the deterministic counterpart to BatchVerify: instead
+// of drawing fresh CSPRNG scalars, it derives every r_i from one Fiat-Shamir
+// transcript over the signature, pubkey, and message of every triple in the
+// batch, so verifying the same batch twice is reproducible. Deriving r_i from only
+// the (attacker-known) pubkeys would let a submitter precompute the
+// scalars offline and craft invalid triples that cancel against them,
+// defeating the batch defense entirely; binding the transcript to every
+// element of every triple rules that out.
+func SecureBatchVerify(curve CurveSystem, sigs []Point1, pubkeys []Point2, msgs [][]byte) (bool, error) {
+	if len(sigs) != len(pubkeys) || len(sigs) != len(msgs) {
+		return false, errors.New("bgls: sigs, pubkeys, and msgs must be the same length")
+	}
+	return batchVerify(curve, sigs, pubkeys, msgs, hashTriplesToExponents(sigs, pubkeys, msgs))
+}
+
+// hashTriplesToExponents is the batch analogue of hashPubKeysToExponents in
+// blsHAE.go: it hashes each (sig, pubkey, msg) triple, in order, to derive
+// one exponent per triple, so the result depends on every element of the
+// batch rather than just the pubkeys.
+func hashTriplesToExponents(sigs []Point1, pubkeys []Point2, msgs [][]byte) []*big.Int {
+	hashFunc, _ := blake2b.NewXOF(uint32(16*len(sigs)), []byte{})
+	for i := range sigs {
+		hashFunc.Write(sigs[i].MarshalUncompressed())
+		hashFunc.Write(pubkeys[i].MarshalUncompressed())
+		hashFunc.Write(msgs[i])
+	}
+	t := make([]*big.Int, len(sigs))
+	for i := range sigs {
+		sum := make([]byte, 16)
+		hashFunc.Read(sum)
+		t[i] = new(big.Int).SetBytes(sum)
+	}
+	return t
+}
+
+func batchVerify(curve CurveSystem, sigs []Point1, pubkeys []Point2, msgs [][]byte, scalars []*big.Int) (bool, error) {
+	if len(sigs) != len(pubkeys) || len(sigs) != len(msgs) || len(sigs) != len(scalars) {
+		return false, errors.New("bgls: sigs, pubkeys, and msgs must be the same length")
+	}
+	scaledSigs := make([]Point1, len(sigs))
+	scaledHashes := make([]Point1, len(sigs))
+	for i := range sigs {
+		h, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msgs[i])
+		if err != nil {
+			return false, err
+		}
+		scaledSigs[i] = sigs[i].Mul(scalars[i])
+		scaledHashes[i] = h.Mul(scalars[i])
+	}
+	aggSig := AggregateG1(scaledSigs)
+	lhs := curve.Pair(aggSig, curve.GetG2())
+	rhs := curve.PairingProduct(scaledHashes, pubkeys)
+	return lhs.Equals(rhs), nil
+}
+
+func randomScalars(n int) ([]*big.Int, error) {
+	max := new(big.Int).Lsh(big.NewInt(1), batchScalarBits)
+	scalars := make([]*big.Int, n)
+	for i := 0; i < n; i++ {
+		r, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return nil, err
+		}
+		scalars[i] = r
+	}
+	return scalars, nil
+}
+
+// BatchVerifier accumulates independent (pubkey, msg, sig) triples and
+// flushes them through BatchVerify on demand, so a caller gossiping many
+// signatures doesn't need to manage its own parallel slices.
+type BatchVerifier struct {
+	curve   CurveSystem
+	sigs    []Point1
+	pubkeys []Point2
+	msgs    [][]byte
+}
+
+// NewBatchVerifier returns an empty BatchVerifier for curve.
+func NewBatchVerifier(curve CurveSystem) *BatchVerifier {
+	return &BatchVerifier{curve: curve}
+}
+
+// Add queues a (pubkey, msg, sig) triple for the next call to Verify.
+func (v *BatchVerifier) Add(pubkey Point2, msg []byte, sig Point1) {
+	v.pubkeys = append(v.pubkeys, pubkey)
+	v.msgs = append(v.msgs, msg)
+	v.sigs = append(v.sigs, sig)
+}
+
+// Verify checks every triple queued since the last call to Verify, via
+// BatchVerify, then clears the queue.
+func (v *BatchVerifier) Verify() (bool, error) {
+	ok, err := BatchVerify(v.curve, v.sigs, v.pubkeys, v.msgs)
+	v.sigs, v.pubkeys, v.msgs = nil, nil, nil
+	return ok, err
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package bgls
+
+import (
+	"math/big"
+	"testing"
+
+	. "github.com/Project-Arda/bgls/curves"
+)
+
+func mustSign(t *testing.T, sk *big.Int, msg []byte) Point1 {
+	t.Helper()
+	sig, err := Sign(fakeCurve{}, sk, msg)
+	if err != nil {
+		t.Fatalf("unexpected error signing %q: %v", msg, err)
+	}
+	return sig
+}
+
+func TestBatchVerifyAcceptsValidTriples(t *testing.T) {
+	curve := fakeCurve{}
+	sks := []*big.Int{big.NewInt(3), big.NewInt(11), big.NewInt(29)}
+	msgs := [][]byte{[]byte("alpha"), []byte("beta"), []byte("gamma")}
+
+	sigs := make([]Point1, len(sks))
+	pubkeys := make([]Point2, len(sks))
+	for i, sk := range sks {
+		sigs[i] = mustSign(t, sk, msgs[i])
+		pubkeys[i] = curve.GetG2().Mul(sk)
+	}
+
+	ok, err := BatchVerify(curve, sigs, pubkeys, msgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected BatchVerify to accept a batch of genuinely valid triples")
+	}
+}
+
+func TestBatchVerifyRejectsTamperedSignature(t *testing.T) {
+	curve := fakeCurve{}
+	sks := []*big.Int{big.NewInt(3), big.NewInt(11)}
+	msgs := [][]byte{[]byte("alpha"), []byte("beta")}
+
+	sigs := make([]Point1, len(sks))
+	pubkeys := make([]Point2, len(sks))
+	for i, sk := range sks {
+		sigs[i] = mustSign(t, sk, msgs[i])
+		pubkeys[i] = curve.GetG2().Mul(sk)
+	}
+	sigs[0] = sigs[0].Mul(big.NewInt(2))
+
+	ok, err := BatchVerify(curve, sigs, pubkeys, msgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected BatchVerify to reject a batch containing a tampered signature")
+	}
+}
+
+func TestBatchVerifyLengthMismatch(t *testing.T) {
+	curve := fakeCurve{}
+	_, err := BatchVerify(curve, []Point1{newFakeG1(1)}, []Point2{newFakeG2(1), newFakeG2(2)}, [][]byte{[]byte("m")})
+	if err == nil {
+		t.Fatalf("expected mismatched slice lengths to be rejected")
+	}
+}
+
+func TestSecureBatchVerifyIsDeterministic(t *testing.T) {
+	curve := fakeCurve{}
+	sks := []*big.Int{big.NewInt(5), big.NewInt(13)}
+	msgs := [][]byte{[]byte("alpha"), []byte("beta")}
+
+	sigs := make([]Point1, len(sks))
+	pubkeys := make([]Point2, len(sks))
+	for i, sk := range sks {
+		sigs[i] = mustSign(t, sk, msgs[i])
+		pubkeys[i] = curve.GetG2().Mul(sk)
+	}
+
+	first, err := SecureBatchVerify(curve, sigs, pubkeys, msgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := SecureBatchVerify(curve, sigs, pubkeys, msgs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !first || first != second {
+		t.Fatalf("expected SecureBatchVerify to deterministically accept the same batch twice, got %v then %v", first, second)
+	}
+}
+
+// TestHashTriplesToExponentsBindsAllFields guards the fix that made
+// SecureBatchVerify derive its scalars from the full (sig, pubkey, msg)
+// triple instead of the pubkeys alone: a submitter who can predict the
+// scalars from public keys alone could craft invalid triples that cancel
+// against them.
+func TestHashTriplesToExponentsBindsAllFields(t *testing.T) {
+	sig, pubkey, msg := newFakeG1(1), newFakeG2(2), []byte("m")
+	base := hashTriplesToExponents([]Point1{sig}, []Point2{pubkey}, [][]byte{msg})[0]
+
+	if t2 := hashTriplesToExponents([]Point1{newFakeG1(2)}, []Point2{pubkey}, [][]byte{msg})[0]; t2.Cmp(base) == 0 {
+		t.Fatalf("expected changing the signature to change the derived exponent")
+	}
+	if t2 := hashTriplesToExponents([]Point1{sig}, []Point2{newFakeG2(3)}, [][]byte{msg})[0]; t2.Cmp(base) == 0 {
+		t.Fatalf("expected changing the pubkey to change the derived exponent")
+	}
+	if t2 := hashTriplesToExponents([]Point1{sig}, []Point2{pubkey}, [][]byte{[]byte("n")})[0]; t2.Cmp(base) == 0 {
+		t.Fatalf("expected changing the message to change the derived exponent")
+	}
+}
+
+func TestRandomScalarsAreBounded(t *testing.T) {
+	scalars, err := randomScalars(50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	max := new(big.Int).Lsh(big.NewInt(1), batchScalarBits)
+	for i, s := range scalars {
+		if s.Sign() < 0 || s.Cmp(max) >= 0 {
+			t.Fatalf("scalar %d = %v is outside [0, 2^%d)", i, s, batchScalarBits)
+		}
+	}
+}
+
+func TestBatchVerifierAccumulatesAndClears(t *testing.T) {
+	curve := fakeCurve{}
+	v := NewBatchVerifier(curve)
+	sk := big.NewInt(7)
+	msg := []byte("alpha")
+	v.Add(curve.GetG2().Mul(sk), msg, mustSign(t, sk, msg))
+
+	ok, err := v.Verify()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected the queued triple to verify")
+	}
+	if len(v.sigs) != 0 || len(v.pubkeys) != 0 || len(v.msgs) != 0 {
+		t.Fatalf("expected Verify to clear the queue")
+	}
+}
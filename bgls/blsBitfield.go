@@ -0,0 +1,196 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package bgls
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	. "github.com/Project-Arda/bgls/curves"
+)
+
+// Bitfield tracks which indices of a fixed validator set contributed to an
+// aggregate, one bit per validator, matching how Ethereum consensus clients
+// (Nimbus, Prysm) record attestation participation alongside the aggregate
+// signature itself.
+type Bitfield struct {
+	bits *big.Int
+}
+
+// NewBitfield returns an empty Bitfield.
+func NewBitfield() *Bitfield {
+	return &Bitfield{bits: new(big.Int)}
+}
+
+func (b *Bitfield) set(idx int)   { b.bits.SetBit(b.bits, idx, 1) }
+func (b *Bitfield) clear(idx int) { b.bits.SetBit(b.bits, idx, 0) }
+
+// IsSet reports whether idx has been recorded as a contributor.
+func (b *Bitfield) IsSet(idx int) bool { return b.bits.Bit(idx) == 1 }
+
+// Overlaps reports whether b and other share any set bit.
+func (b *Bitfield) Overlaps(other *Bitfield) bool {
+	return new(big.Int).And(b.bits, other.bits).Sign() != 0
+}
+
+// Marshal returns the big-endian byte encoding of the bitfield.
+func (b *Bitfield) Marshal() []byte { return b.bits.Bytes() }
+
+// UnmarshalBitfield parses the big-endian encoding produced by Marshal.
+func UnmarshalBitfield(raw []byte) *Bitfield {
+	return &Bitfield{bits: new(big.Int).SetBytes(raw)}
+}
+
+// BitAggregate is an aggregate signature over a fixed, ordered validator
+// set, together with a Bitfield recording which of those validators
+// contributed. Unlike AggregateSignaturesWithHAE, which only takes flat
+// slices, a BitAggregate can be built up incrementally, merged with another
+// non-overlapping aggregate, or have a signer removed, because it always
+// knows which HAE exponent a contributor was scaled by: the one derived
+// from the full validator set, not just the contributors present so far.
+// Those exponents are derived once, in NewBitAggregate, rather than on every
+// Add/Subtract/Verify call, so building up an aggregate one validator at a
+// time is O(n) over the validator set instead of O(n^2).
+type BitAggregate struct {
+	validators []Point2
+	exponents  []*big.Int
+	aggSig     Point1
+	bits       *Bitfield
+}
+
+// NewBitAggregate returns an empty BitAggregate over the given ordered
+// validator set, precomputing the HAE exponents the set will be scaled by.
+func NewBitAggregate(validators []Point2) *BitAggregate {
+	return &BitAggregate{
+		validators: validators,
+		exponents:  hashPubKeysToExponents(validators),
+		bits:       NewBitfield(),
+	}
+}
+
+// Add folds sig, validators[idx]'s raw (un-scaled) signature, into the
+// aggregate, scaling it by that validator's HAE exponent over the full set.
+func (a *BitAggregate) Add(idx int, sig Point1) error {
+	if idx < 0 || idx >= len(a.validators) {
+		return errors.New("bgls: validator index out of range")
+	}
+	if a.bits.IsSet(idx) {
+		return errors.New("bgls: validator already in aggregate")
+	}
+	scaled := sig.Mul(a.exponents[idx])
+	if a.aggSig == nil {
+		a.aggSig = scaled
+	} else {
+		a.aggSig = AggregateG1([]Point1{a.aggSig, scaled})
+	}
+	a.bits.set(idx)
+	return nil
+}
+
+// Subtract removes validators[idx]'s contribution from the aggregate, given
+// its raw (un-scaled) signature.
+func (a *BitAggregate) Subtract(idx int, sig Point1) error {
+	if idx < 0 || idx >= len(a.validators) {
+		return errors.New("bgls: validator index out of range")
+	}
+	if !a.bits.IsSet(idx) {
+		return errors.New("bgls: validator not in aggregate")
+	}
+	negated := sig.Mul(a.exponents[idx]).Mul(big.NewInt(-1))
+	a.aggSig = AggregateG1([]Point1{a.aggSig, negated})
+	a.bits.clear(idx)
+	return nil
+}
+
+// Merge folds other's contribution into a. It is an error if the two
+// aggregates' bitfields overlap, since that would double-count a signer, or
+// if they aren't built over the same ordered validator set, since each
+// side's HAE exponents would then have been derived from a different
+// ordering.
+func (a *BitAggregate) Merge(other *BitAggregate) error {
+	if len(a.validators) != len(other.validators) {
+		return errors.New("bgls: cannot merge aggregates over different validator sets")
+	}
+	for i := range a.validators {
+		if !a.validators[i].Equals(other.validators[i]) {
+			return errors.New("bgls: cannot merge aggregates over different validator sets")
+		}
+	}
+	if a.bits.Overlaps(other.bits) {
+		return errors.New("bgls: cannot merge aggregates with overlapping signers")
+	}
+	if other.aggSig == nil {
+		return nil
+	}
+	if a.aggSig == nil {
+		a.aggSig = other.aggSig
+	} else {
+		a.aggSig = AggregateG1([]Point1{a.aggSig, other.aggSig})
+	}
+	a.bits.bits.Or(a.bits.bits, other.bits.bits)
+	return nil
+}
+
+// Verify checks the aggregate signature against msg, scaling each
+// bits-selected validator's pubkey by its HAE exponent over the full set.
+// msg is first rejected if it would hash to a dangerous point (see
+// curves.HashToG1WithDST).
+func (a *BitAggregate) Verify(curve CurveSystem, msg []byte) bool {
+	if a.aggSig == nil {
+		return false
+	}
+	if _, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msg); err != nil {
+		return false
+	}
+	var scaled []Point2
+	for i, pk := range a.validators {
+		if a.bits.IsSet(i) {
+			scaled = append(scaled, pk.Mul(a.exponents[i]))
+		}
+	}
+	if len(scaled) == 0 {
+		return false
+	}
+	return VerifyMultiSignature(curve, a.aggSig, scaled, msg)
+}
+
+// Marshal serializes the aggregate as a length-prefixed bitfield followed
+// by the compressed signature, so it can travel over the wire alongside the
+// (out-of-band, already-known) validator set. It returns an error if called
+// on an aggregate with no contributions yet, since there is no signature to
+// serialize.
+func (a *BitAggregate) Marshal() ([]byte, error) {
+	if a.aggSig == nil {
+		return nil, errors.New("bgls: cannot marshal an empty BitAggregate")
+	}
+	bits := a.bits.Marshal()
+	out := make([]byte, 4+len(bits))
+	binary.BigEndian.PutUint32(out, uint32(len(bits)))
+	copy(out[4:], bits)
+	return append(out, a.aggSig.Marshal()...), nil
+}
+
+// UnmarshalBitAggregate parses the wire format produced by Marshal against
+// the given (out-of-band) validator set.
+func UnmarshalBitAggregate(curve CurveSystem, validators []Point2, raw []byte) (*BitAggregate, error) {
+	if len(raw) < 4 {
+		return nil, errors.New("bgls: truncated BitAggregate")
+	}
+	n := binary.BigEndian.Uint32(raw)
+	if n > uint32(len(raw)-4) {
+		return nil, errors.New("bgls: truncated BitAggregate")
+	}
+	bits := UnmarshalBitfield(raw[4 : 4+n])
+	sig, ok := curve.UnmarshalG1(raw[4+n:])
+	if !ok {
+		return nil, errors.New("bgls: invalid aggregate signature encoding")
+	}
+	return &BitAggregate{
+		validators: validators,
+		exponents:  hashPubKeysToExponents(validators),
+		aggSig:     sig,
+		bits:       bits,
+	}, nil
+}
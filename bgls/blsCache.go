@@ -0,0 +1,98 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package bgls
+
+import (
+	. "github.com/Project-Arda/bgls/curves"
+)
+
+// DeserializePubkeyCached unmarshals raw into a Point2, consulting cache
+// first so a pubkey seen on a prior verification doesn't pay to be
+// re-parsed and re-subgroup-checked. On a miss, the parsed result is stored
+// in cache for next time.
+func DeserializePubkeyCached(curve CurveSystem, cache *PublicKeyCache, raw []byte) (Point2, bool) {
+	if pk, ok := cache.Get(raw); ok {
+		return pk, true
+	}
+	pk, ok := curve.UnmarshalG2(raw)
+	if !ok {
+		return nil, false
+	}
+	cache.Put(raw, pk)
+	return pk, true
+}
+
+// DeserializeSignatureCached is the Point1 analogue of
+// DeserializePubkeyCached, for caching aggregate signatures that get
+// re-verified across a fan-out of gossip peers.
+func DeserializeSignatureCached(curve CurveSystem, cache *SignatureCache, raw []byte) (Point1, bool) {
+	if sig, ok := cache.Get(raw); ok {
+		return sig, true
+	}
+	sig, ok := curve.UnmarshalG1(raw)
+	if !ok {
+		return nil, false
+	}
+	cache.Put(raw, sig)
+	return sig, true
+}
+
+// deserializePubkeysCached deserializes each of rawPubkeys via
+// DeserializePubkeyCached, so HAE and PoP verification over compressed
+// wire bytes can share the decompression cache instead of each caller
+// re-parsing every pubkey on every call.
+func deserializePubkeysCached(curve CurveSystem, cache *PublicKeyCache, rawPubkeys [][]byte) ([]Point2, bool) {
+	pubkeys := make([]Point2, len(rawPubkeys))
+	for i, raw := range rawPubkeys {
+		pk, ok := DeserializePubkeyCached(curve, cache, raw)
+		if !ok {
+			return nil, false
+		}
+		pubkeys[i] = pk
+	}
+	return pubkeys, true
+}
+
+// VerifyMultiSignatureWithHAECached is VerifyMultiSignatureWithHAE for
+// pubkeys given as compressed wire bytes, deserializing each one through
+// cache so a pubkey seen on a prior verification isn't re-parsed.
+func VerifyMultiSignatureWithHAECached(curve CurveSystem, cache *PublicKeyCache, aggsig Point1, rawPubkeys [][]byte, msg []byte) bool {
+	pubkeys, ok := deserializePubkeysCached(curve, cache, rawPubkeys)
+	if !ok {
+		return false
+	}
+	return VerifyMultiSignatureWithHAE(curve, aggsig, pubkeys, msg)
+}
+
+// VerifyAggregateSignatureWithHAECached is VerifyAggregateSignatureWithHAE
+// for pubkeys given as compressed wire bytes, deserializing each one
+// through cache.
+func VerifyAggregateSignatureWithHAECached(curve CurveSystem, cache *PublicKeyCache, aggsig Point1, rawPubkeys [][]byte, msgs [][]byte) bool {
+	pubkeys, ok := deserializePubkeysCached(curve, cache, rawPubkeys)
+	if !ok {
+		return false
+	}
+	return VerifyAggregateSignatureWithHAE(curve, aggsig, pubkeys, msgs)
+}
+
+// VerifyPoPCached is VerifyPoP for a pubkey given as compressed wire bytes,
+// deserializing it through cache.
+func VerifyPoPCached(curve CurveSystem, cache *PublicKeyCache, rawPubkey []byte, pop Point1) bool {
+	pk, ok := DeserializePubkeyCached(curve, cache, rawPubkey)
+	if !ok {
+		return false
+	}
+	return VerifyPoP(curve, pk, pop)
+}
+
+// VerifyMultiSignatureWithPoPCached is VerifyMultiSignatureWithPoP for
+// pubkeys given as compressed wire bytes, deserializing each one through
+// cache.
+func VerifyMultiSignatureWithPoPCached(curve CurveSystem, cache *PublicKeyCache, aggsig Point1, rawPubkeys [][]byte, pops []Point1, msg []byte) bool {
+	pubkeys, ok := deserializePubkeysCached(curve, cache, rawPubkeys)
+	if !ok {
+		return false
+	}
+	return VerifyMultiSignatureWithPoP(curve, aggsig, pubkeys, pops, msg)
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package bgls
+
+import (
+	"math/big"
+	"testing"
+
+	. "github.com/Project-Arda/bgls/curves"
+)
+
+func TestDeserializePubkeyCachedHitsAvoidReparsing(t *testing.T) {
+	curve := fakeCurve{}
+	cache := NewPublicKeyCache(8)
+	raw := newFakeG2(5).Marshal()
+
+	pk, ok := DeserializePubkeyCached(curve, cache, raw)
+	if !ok {
+		t.Fatalf("expected the first (miss) call to succeed")
+	}
+	cached, ok := cache.Get(raw)
+	if !ok || !cached.Equals(pk) {
+		t.Fatalf("expected a miss to populate the cache with the parsed pubkey")
+	}
+
+	again, ok := DeserializePubkeyCached(curve, cache, raw)
+	if !ok || !again.Equals(pk) {
+		t.Fatalf("expected a cache hit to return the same pubkey as the original parse")
+	}
+
+	// Replace the entry with a different point: a hit must return what the
+	// cache holds rather than re-parsing raw.
+	cache.Put(raw, newFakeG2(6))
+	hit, ok := DeserializePubkeyCached(curve, cache, raw)
+	if !ok || !hit.Equals(newFakeG2(6)) {
+		t.Fatalf("expected a cache hit to return the cached pubkey without re-parsing raw")
+	}
+}
+
+func TestVerifyMultiSignatureWithHAECachedMatchesUncached(t *testing.T) {
+	curve := fakeCurve{}
+	cache := NewPublicKeyCache(8)
+	sks := []*big.Int{big.NewInt(3), big.NewInt(11)}
+	msg := []byte("alpha")
+
+	pubkeys := make([]Point2, len(sks))
+	rawPubkeys := make([][]byte, len(sks))
+	sigs := make([]Point1, len(sks))
+	for i, sk := range sks {
+		pubkeys[i] = curve.GetG2().Mul(sk)
+		rawPubkeys[i] = pubkeys[i].Marshal()
+		sigs[i] = mustSign(t, sk, msg)
+	}
+	aggsig := AggregateSignaturesWithHAE(sigs, append([]Point2{}, pubkeys...))
+
+	cached := VerifyMultiSignatureWithHAECached(curve, cache, aggsig, rawPubkeys, msg)
+	uncached := VerifyMultiSignatureWithHAE(curve, aggsig, append([]Point2{}, pubkeys...), msg)
+	if cached != uncached {
+		t.Fatalf("expected the cached and uncached verification paths to agree, got %v and %v", cached, uncached)
+	}
+	if !cached {
+		t.Fatalf("expected the cached verification path to accept a genuinely valid aggregate")
+	}
+}
+
+// BenchmarkDeserializePubkeyCached measures the win DeserializePubkeyCached
+// is for: the same pubkey bytes arriving repeatedly, as they do when many
+// gossip peers forward the same validator's signature.
+func BenchmarkDeserializePubkeyCached(b *testing.B) {
+	curve := fakeCurve{}
+	cache := NewPublicKeyCache(8)
+	raw := newFakeG2(5).Marshal()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := DeserializePubkeyCached(curve, cache, raw); !ok {
+			b.Fatalf("unexpected unmarshal failure")
+		}
+	}
+}
+
+// BenchmarkDeserializePubkeyUncached is the baseline: every call re-parses
+// raw from scratch via curve.UnmarshalG2, with no cache involved.
+func BenchmarkDeserializePubkeyUncached(b *testing.B) {
+	curve := fakeCurve{}
+	raw := newFakeG2(5).Marshal()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, ok := curve.UnmarshalG2(raw); !ok {
+			b.Fatalf("unexpected unmarshal failure")
+		}
+	}
+}
@@ -26,7 +26,10 @@ import (
 
 // AggregateSignaturesWithHAE aggregates the signatures, using the
 // hashed exponents derived from the pubkeys to protect against the rogue
-// public key attack.
+// public key attack. It operates on sigs that were already produced by
+// hashing a message to a curve point and signing it, so the
+// dangerous-point guard (see curves.HashToG1WithDST) belongs at that
+// hashing step, and at the verification functions below, rather than here.
 func AggregateSignaturesWithHAE(sigs []Point1, pubkeys []Point2) Point1 {
 	if len(pubkeys) != len(sigs) {
 		return nil
@@ -39,8 +42,18 @@ func AggregateSignaturesWithHAE(sigs []Point1, pubkeys []Point2) Point1 {
 	return AggregateG1(newsigs)
 }
 
-// VerifyAggregateSignatureWithHAE verifies signatures of different messages aggregated with HAE.
+// VerifyAggregateSignatureWithHAE verifies signatures of different messages
+// aggregated with HAE. Each message is hashed under the CiphersuiteBasic
+// domain tag and first rejected if it would hash to a dangerous point (see
+// curves.HashToG1WithDST), so a crafted message can't be used to forge a
+// signature against a trivially-known discrete log -- or against a PoP,
+// which is tagged with the distinct CiphersuitePoP.
 func VerifyAggregateSignatureWithHAE(curve CurveSystem, aggsig Point1, pubkeys []Point2, msgs [][]byte) bool {
+	for _, msg := range msgs {
+		if _, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msg); err != nil {
+			return false
+		}
+	}
 	t := hashPubKeysToExponents(pubkeys)
 	newkeys := make([]Point2, len(pubkeys))
 	for i := 0; i < len(pubkeys); i++ {
@@ -49,8 +62,14 @@ func VerifyAggregateSignatureWithHAE(curve CurveSystem, aggsig Point1, pubkeys [
 	return verifyAggSig(curve, aggsig, newkeys, msgs, true)
 }
 
-// VerifyMultiSignatureWithHAE verifies signatures of the same message aggregated with HAE.
+// VerifyMultiSignatureWithHAE verifies signatures of the same message
+// aggregated with HAE. msg is hashed under the CiphersuiteBasic domain tag
+// and first rejected if it would hash to a dangerous point (see
+// curves.HashToG1WithDST).
 func VerifyMultiSignatureWithHAE(curve CurveSystem, aggsig Point1, pubkeys []Point2, msg []byte) bool {
+	if _, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msg); err != nil {
+		return false
+	}
 	t := hashPubKeysToExponents(pubkeys)
 	for i := 0; i < len(pubkeys); i++ {
 		pubkeys[i] = pubkeys[i].Mul(t[i])
@@ -0,0 +1,103 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package bgls
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	. "github.com/Project-Arda/bgls/curves"
+)
+
+// This file wraps bgls's existing primitives (KeyGen, Sign, Verify,
+// Aggregate) in the API surface described by draft-irtf-cfrg-bls-signature,
+// as implemented by Eth2 libraries such as herumi/bls-eth-go-binary and
+// blst. The ciphersuite constants below are the domain separation tags
+// passed to HashToG1WithDST. Every message signature in this package, whether
+// its aggregate is guarded by HAE (blsHAE.go) or by PoP (blsPoP.go), is
+// hashed under CiphersuiteBasic; CiphersuitePoP tags only the proofs of
+// possession themselves. CiphersuiteAug is defined, but no function in this
+// package signs or verifies under it: message augmentation is not supported.
+const (
+	CiphersuiteBasic = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_NUL_"
+	CiphersuiteAug   = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_AUG_"
+	CiphersuitePoP   = "BLS_SIG_BLS12381G1_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+// KeyGen draws a fresh secret key uniformly from [0, curve's G1 order) and
+// returns it alongside the corresponding public key in G2.
+func KeyGen(curve CurveSystem) (*big.Int, Point2, error) {
+	sk, err := rand.Int(rand.Reader, curve.GetG1Order())
+	if err != nil {
+		return nil, nil, err
+	}
+	return sk, curve.GetG2().Mul(sk), nil
+}
+
+// Sign signs msg under sk, hashing it to G1 under the CiphersuiteBasic
+// domain tag. It returns ErrDangerousPoint in the vanishingly unlikely case
+// that msg hashes to a point with a known discrete log (see
+// curves.HashToG1WithDST).
+func Sign(curve CurveSystem, sk *big.Int, msg []byte) (Point1, error) {
+	h, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msg)
+	if err != nil {
+		return nil, err
+	}
+	return h.Mul(sk), nil
+}
+
+// Verify checks sig against pk and msg with a single pairing. Like Sign, it
+// hashes msg under the CiphersuiteBasic domain tag and rejects it outright
+// if that would produce a dangerous point. Verify does not protect against
+// rogue keys on its own; see AggregateVerify and FastAggregateVerify for the
+// aggregate case.
+func Verify(curve CurveSystem, pk Point2, msg []byte, sig Point1) bool {
+	h, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msg)
+	if err != nil {
+		return false
+	}
+	return curve.Pair(sig, curve.GetG2()).Equals(curve.Pair(h, pk))
+}
+
+// Aggregate sums sigs into a single Point1, the IETF name for AggregateG1.
+func Aggregate(sigs []Point1) Point1 {
+	return AggregateG1(sigs)
+}
+
+// AggregatePublicKeys sums pubkeys into a single Point2, so the result can
+// be cached and reused across many FastAggregateVerify calls against the
+// same validator set instead of re-summing on every call.
+func AggregatePublicKeys(pubkeys []Point2) Point2 {
+	return AggregateG2(pubkeys)
+}
+
+// AggregateVerify verifies aggsig against distinct (pubkey, msg) pairs, one
+// pairing per key. It does not protect against rogue keys on its own;
+// pubkeys must already be HAE-scaled or PoP-registered before aggregation.
+// Each message is hashed under the CiphersuiteBasic domain tag and first
+// rejected if it would hash to a dangerous point (see
+// curves.HashToG1WithDST).
+func AggregateVerify(curve CurveSystem, aggsig Point1, pubkeys []Point2, msgs [][]byte) bool {
+	for _, msg := range msgs {
+		if _, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msg); err != nil {
+			return false
+		}
+	}
+	return verifyAggSig(curve, aggsig, pubkeys, msgs, false)
+}
+
+// FastAggregateVerify verifies aggsig against a single msg and a
+// pre-aggregated aggPubkey, doing e(sig, g2) == e(H(m), aggPk) in one
+// pairing. Unlike VerifyMultiSignatureWithHAE, it does not mutate its
+// caller's pubkeys and does not require HAE; callers are expected to have
+// gated rogue keys with PoP (see AggregateSignaturesWithPoP) before
+// aggregating them with AggregatePublicKeys. msg is hashed under the
+// CiphersuiteBasic domain tag and first rejected if it would hash to a
+// dangerous point (see curves.HashToG1WithDST).
+func FastAggregateVerify(curve CurveSystem, aggsig Point1, aggPubkey Point2, msg []byte) bool {
+	if _, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msg); err != nil {
+		return false
+	}
+	return VerifyMultiSignature(curve, aggsig, []Point2{aggPubkey}, msg)
+}
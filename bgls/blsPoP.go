@@ -0,0 +1,106 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package bgls
+
+import (
+	"errors"
+	"math/big"
+
+	. "github.com/Project-Arda/bgls/curves"
+)
+
+// Proof-of-Possession (PoP) is an alternative to Hashed Aggregation
+// Exponents (see blsHAE.go) for defending against the rogue public key
+// attack, as used by Ethereum/Eth2 clients. Each signer proves once, out of
+// band, that they hold the secret key behind their public key. Once every
+// pubkey in a set has a verified PoP, verifiers can sum the raw pubkeys and
+// check a common-message aggregate with a single pairing, instead of
+// rehashing and rescaling every pubkey on every verification the way HAE
+// requires. The PoP-gated functions below take the PoPs as arguments and
+// re-verify every one of them on each call; the single-pairing check over
+// pubkeys whose PoPs were verified earlier is FastAggregateVerify
+// (blsIETF.go).
+
+// GeneratePoP generates a Proof-of-Possession for pk: a signature, under sk,
+// of pk's marshaled bytes hashed under the CiphersuitePoP domain separation
+// tag. Hashing under a dedicated tag, rather than the bare message hasher
+// ordinary signing uses, is what makes a PoP unforgeable from an ordinary
+// signature: see HashToG1WithDST for why every hash-to-curve call in this
+// package, including ordinary Sign/Verify, must be tagged this way. It
+// returns ErrDangerousPoint in the vanishingly unlikely case that pk hashes
+// to a point with a known discrete log.
+func GeneratePoP(curve CurveSystem, sk *big.Int, pk Point2) (Point1, error) {
+	h, err := HashToG1WithDST(curve, []byte(CiphersuitePoP), pk.MarshalUncompressed())
+	if err != nil {
+		return nil, err
+	}
+	return h.Mul(sk), nil
+}
+
+// VerifyPoP verifies that pop is a valid Proof-of-Possession for pk.
+func VerifyPoP(curve CurveSystem, pk Point2, pop Point1) bool {
+	h, err := HashToG1WithDST(curve, []byte(CiphersuitePoP), pk.MarshalUncompressed())
+	if err != nil {
+		return false
+	}
+	return curve.Pair(pop, curve.GetG2()).Equals(curve.Pair(h, pk))
+}
+
+// AggregateSignaturesWithPoP aggregates sigs as a plain sum, after checking
+// that every signer in pubkeys has supplied a valid PoP for their key.
+// Unlike AggregateSignaturesWithHAE, no rescaling of the signatures is
+// needed, since the rogue-key defense is front-loaded onto key
+// registration rather than applied at aggregation time.
+func AggregateSignaturesWithPoP(curve CurveSystem, sigs []Point1, pubkeys []Point2, pops []Point1) (Point1, error) {
+	if len(sigs) != len(pubkeys) || len(sigs) != len(pops) {
+		return nil, errors.New("bgls: sigs, pubkeys, and pops must be the same length")
+	}
+	for i := range pubkeys {
+		if !VerifyPoP(curve, pubkeys[i], pops[i]) {
+			return nil, errors.New("bgls: invalid proof-of-possession")
+		}
+	}
+	return AggregateG1(sigs), nil
+}
+
+// VerifyMultiSignatureWithPoP verifies aggsig, a PoP-gated aggregate of
+// signatures over the same msg, by checking each pubkey's PoP and then
+// summing the raw pubkeys for a single pairing check. msg is first
+// rejected if it would hash to a dangerous point (see
+// curves.HashToG1WithDST).
+func VerifyMultiSignatureWithPoP(curve CurveSystem, aggsig Point1, pubkeys []Point2, pops []Point1, msg []byte) bool {
+	if len(pubkeys) != len(pops) {
+		return false
+	}
+	if _, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msg); err != nil {
+		return false
+	}
+	for i := range pubkeys {
+		if !VerifyPoP(curve, pubkeys[i], pops[i]) {
+			return false
+		}
+	}
+	return VerifyMultiSignature(curve, aggsig, pubkeys, msg)
+}
+
+// VerifyAggregateSignatureWithPoP verifies aggsig, a PoP-gated aggregate of
+// signatures over distinct msgs, by checking each pubkey's PoP before
+// falling back to one pairing per key. Each msg is first rejected if it
+// would hash to a dangerous point (see curves.HashToG1WithDST).
+func VerifyAggregateSignatureWithPoP(curve CurveSystem, aggsig Point1, pubkeys []Point2, pops []Point1, msgs [][]byte) bool {
+	if len(pubkeys) != len(pops) {
+		return false
+	}
+	for _, msg := range msgs {
+		if _, err := HashToG1WithDST(curve, []byte(CiphersuiteBasic), msg); err != nil {
+			return false
+		}
+	}
+	for i := range pubkeys {
+		if !VerifyPoP(curve, pubkeys[i], pops[i]) {
+			return false
+		}
+	}
+	return verifyAggSig(curve, aggsig, pubkeys, msgs, true)
+}
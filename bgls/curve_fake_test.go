@@ -0,0 +1,102 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package bgls
+
+import (
+	"math/big"
+
+	. "github.com/Project-Arda/bgls/curves"
+)
+
+// fakeCurveOrder is the toy group order backing the CurveSystem test double
+// below. It has no cryptographic meaning; it's just large enough that the
+// values these tests push through it, including BatchVerify's random
+// per-triple scalars, don't collide by accident.
+var fakeCurveOrder = big.NewInt(104729)
+
+// fakeG1Point and fakeG2Point implement Point1 and Point2 as the additive
+// group of integers mod fakeCurveOrder. That's enough arithmetic to drive
+// BitAggregate, BatchVerify, and the HAE/PoP exponent math in tests without
+// a real pairing-friendly curve.
+type fakeG1Point struct{ v *big.Int }
+type fakeG2Point struct{ v *big.Int }
+
+func newFakeG1(n int64) fakeG1Point { return fakeG1Point{v: big.NewInt(n)} }
+func newFakeG2(n int64) fakeG2Point { return fakeG2Point{v: big.NewInt(n)} }
+
+func (p fakeG1Point) Mul(s *big.Int) Point1 {
+	return fakeG1Point{v: new(big.Int).Mod(new(big.Int).Mul(p.v, s), fakeCurveOrder)}
+}
+func (p fakeG1Point) Equals(other Point1) bool    { return p.v.Cmp(other.(fakeG1Point).v) == 0 }
+func (p fakeG1Point) Marshal() []byte             { return p.v.Bytes() }
+func (p fakeG1Point) MarshalUncompressed() []byte { return p.v.Bytes() }
+
+// Add is not part of Point1, but lets this package's fake AggregateG1 stub
+// (see the build harness) fold points the same way a real curve's
+// AggregateG1 would.
+func (p fakeG1Point) Add(other Point1) Point1 {
+	return fakeG1Point{v: new(big.Int).Mod(new(big.Int).Add(p.v, other.(fakeG1Point).v), fakeCurveOrder)}
+}
+
+func (p fakeG2Point) Mul(s *big.Int) Point2 {
+	return fakeG2Point{v: new(big.Int).Mod(new(big.Int).Mul(p.v, s), fakeCurveOrder)}
+}
+func (p fakeG2Point) Equals(other Point2) bool    { return p.v.Cmp(other.(fakeG2Point).v) == 0 }
+func (p fakeG2Point) Marshal() []byte             { return p.v.Bytes() }
+func (p fakeG2Point) MarshalUncompressed() []byte { return p.v.Bytes() }
+
+func (p fakeG2Point) Add(other Point2) Point2 {
+	return fakeG2Point{v: new(big.Int).Mod(new(big.Int).Add(p.v, other.(fakeG2Point).v), fakeCurveOrder)}
+}
+
+// fakePairResult is the toy pairing output: plain integer multiplication is
+// trivially bilinear, which is all the CurveSystem test double needs to be.
+type fakePairResult struct{ v *big.Int }
+
+func (r fakePairResult) Equals(other PairingResult) bool {
+	return r.v.Cmp(other.(fakePairResult).v) == 0
+}
+
+// fakeCurve is a minimal CurveSystem test double good enough to drive the
+// package's hash-to-curve call sites and pairing checks without a real
+// pairing-friendly curve. HashToG1/HashToG2 hash by summing input bytes mod
+// fakeCurveOrder.
+type fakeCurve struct{}
+
+func (fakeCurve) GetG1() Point1        { return newFakeG1(1) }
+func (fakeCurve) GetG2() Point2        { return newFakeG2(1) }
+func (fakeCurve) GetG1Order() *big.Int { return fakeCurveOrder }
+
+func (fakeCurve) HashToG1(msg []byte) Point1 {
+	return fakeG1Point{v: new(big.Int).Mod(sumBytes(msg), fakeCurveOrder)}
+}
+func (fakeCurve) HashToG2(msg []byte) Point2 {
+	return fakeG2Point{v: new(big.Int).Mod(sumBytes(msg), fakeCurveOrder)}
+}
+
+func (fakeCurve) Pair(a Point1, b Point2) PairingResult {
+	return fakePairResult{v: new(big.Int).Mod(new(big.Int).Mul(a.(fakeG1Point).v, b.(fakeG2Point).v), fakeCurveOrder)}
+}
+func (c fakeCurve) PairingProduct(as []Point1, bs []Point2) PairingResult {
+	sum := big.NewInt(0)
+	for i := range as {
+		sum.Add(sum, c.Pair(as[i], bs[i]).(fakePairResult).v)
+	}
+	return fakePairResult{v: sum.Mod(sum, fakeCurveOrder)}
+}
+
+func (fakeCurve) UnmarshalG1(raw []byte) (Point1, bool) {
+	return fakeG1Point{v: new(big.Int).Mod(new(big.Int).SetBytes(raw), fakeCurveOrder)}, true
+}
+func (fakeCurve) UnmarshalG2(raw []byte) (Point2, bool) {
+	return fakeG2Point{v: new(big.Int).Mod(new(big.Int).SetBytes(raw), fakeCurveOrder)}, true
+}
+
+func sumBytes(b []byte) *big.Int {
+	sum := big.NewInt(0)
+	for _, c := range b {
+		sum.Add(sum, big.NewInt(int64(c)))
+	}
+	return sum
+}
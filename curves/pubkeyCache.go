@@ -0,0 +1,141 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package curves
+
+import (
+	"container/list"
+	"sync"
+)
+
+// PublicKeyCache memoizes the decompression (and subgroup check) of G2
+// points keyed on their raw compressed bytes, so a repeat verifier (e.g.
+// revalidating the same block gossip as it arrives from many peers) does
+// not pay to re-parse the same pubkey over and over. It evicts the
+// least-recently-used entry once it holds maxEntries points. A
+// PublicKeyCache is safe for concurrent use by multiple goroutines, since
+// the gossip re-verification it's built for is inherently concurrent.
+type PublicKeyCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type pubkeyCacheEntry struct {
+	key string
+	pk  Point2
+}
+
+// NewPublicKeyCache creates a PublicKeyCache holding at most maxEntries
+// decompressed pubkeys. A maxEntries of zero or less disables eviction.
+func NewPublicKeyCache(maxEntries int) *PublicKeyCache {
+	return &PublicKeyCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached Point2 for the compressed bytes raw, if present.
+func (c *PublicKeyCache) Get(raw []byte) (Point2, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elt, ok := c.items[string(raw)]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(elt)
+	return elt.Value.(*pubkeyCacheEntry).pk, true
+}
+
+// Put records pk as the decompressed value for the compressed bytes raw,
+// evicting the least-recently-used entry if the cache is already full.
+func (c *PublicKeyCache) Put(raw []byte, pk Point2) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := string(raw)
+	if elt, ok := c.items[key]; ok {
+		elt.Value.(*pubkeyCacheEntry).pk = pk
+		c.ll.MoveToFront(elt)
+		return
+	}
+	elt := c.ll.PushFront(&pubkeyCacheEntry{key: key, pk: pk})
+	c.items[key] = elt
+	if c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		c.removeOldest()
+	}
+}
+
+func (c *PublicKeyCache) removeOldest() {
+	elt := c.ll.Back()
+	if elt == nil {
+		return
+	}
+	c.ll.Remove(elt)
+	delete(c.items, elt.Value.(*pubkeyCacheEntry).key)
+}
+
+// SignatureCache is the Point1 analogue of PublicKeyCache, for memoizing
+// decompressed aggregate signatures across repeat verifications. It is
+// likewise safe for concurrent use.
+type SignatureCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type sigCacheEntry struct {
+	key string
+	sig Point1
+}
+
+// NewSignatureCache creates a SignatureCache holding at most maxEntries
+// decompressed signatures. A maxEntries of zero or less disables eviction.
+func NewSignatureCache(maxEntries int) *SignatureCache {
+	return &SignatureCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached Point1 for the compressed bytes raw, if present.
+func (c *SignatureCache) Get(raw []byte) (Point1, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elt, ok := c.items[string(raw)]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(elt)
+	return elt.Value.(*sigCacheEntry).sig, true
+}
+
+// Put records sig as the decompressed value for the compressed bytes raw,
+// evicting the least-recently-used entry if the cache is already full.
+func (c *SignatureCache) Put(raw []byte, sig Point1) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := string(raw)
+	if elt, ok := c.items[key]; ok {
+		elt.Value.(*sigCacheEntry).sig = sig
+		c.ll.MoveToFront(elt)
+		return
+	}
+	elt := c.ll.PushFront(&sigCacheEntry{key: key, sig: sig})
+	c.items[key] = elt
+	if c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		c.removeOldest()
+	}
+}
+
+func (c *SignatureCache) removeOldest() {
+	elt := c.ll.Back()
+	if elt == nil {
+		return
+	}
+	c.ll.Remove(elt)
+	delete(c.items, elt.Value.(*sigCacheEntry).key)
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package curves
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestPublicKeyCacheGetPutRoundtrip(t *testing.T) {
+	c := NewPublicKeyCache(8)
+	raw := []byte("pubkey-a")
+	if _, ok := c.Get(raw); ok {
+		t.Fatalf("expected a miss on an empty cache")
+	}
+	pk := fakeG2Point{v: big.NewInt(1)}
+	c.Put(raw, pk)
+	got, ok := c.Get(raw)
+	if !ok || !got.Equals(pk) {
+		t.Fatalf("expected Get to return the pubkey just Put, got %v, %v", got, ok)
+	}
+}
+
+func TestPublicKeyCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewPublicKeyCache(2)
+	c.Put([]byte("a"), fakeG2Point{v: big.NewInt(1)})
+	c.Put([]byte("b"), fakeG2Point{v: big.NewInt(2)})
+	c.Put([]byte("c"), fakeG2Point{v: big.NewInt(3)})
+
+	if _, ok := c.Get([]byte("a")); ok {
+		t.Fatalf("expected the least-recently-used entry to have been evicted")
+	}
+	if _, ok := c.Get([]byte("b")); !ok {
+		t.Fatalf("expected b to still be cached")
+	}
+	if _, ok := c.Get([]byte("c")); !ok {
+		t.Fatalf("expected c to still be cached")
+	}
+}
+
+func TestPublicKeyCacheGetRefreshesRecency(t *testing.T) {
+	c := NewPublicKeyCache(2)
+	c.Put([]byte("a"), fakeG2Point{v: big.NewInt(1)})
+	c.Put([]byte("b"), fakeG2Point{v: big.NewInt(2)})
+	// Touching a moves it to the front, so b should be evicted next instead.
+	c.Get([]byte("a"))
+	c.Put([]byte("c"), fakeG2Point{v: big.NewInt(3)})
+
+	if _, ok := c.Get([]byte("b")); ok {
+		t.Fatalf("expected b, not a, to have been evicted after a was refreshed")
+	}
+	if _, ok := c.Get([]byte("a")); !ok {
+		t.Fatalf("expected a to survive eviction after being refreshed")
+	}
+}
+
+func TestPublicKeyCacheConcurrentAccess(t *testing.T) {
+	c := NewPublicKeyCache(16)
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := []byte{byte(i % 8)}
+			c.Put(key, fakeG2Point{v: big.NewInt(int64(i))})
+			c.Get(key)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSignatureCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewSignatureCache(1)
+	c.Put([]byte("a"), fakeG1Point{v: big.NewInt(1)})
+	c.Put([]byte("b"), fakeG1Point{v: big.NewInt(2)})
+
+	if _, ok := c.Get([]byte("a")); ok {
+		t.Fatalf("expected a to have been evicted once the cache exceeded maxEntries")
+	}
+	got, ok := c.Get([]byte("b"))
+	if !ok || !got.Equals(fakeG1Point{v: big.NewInt(2)}) {
+		t.Fatalf("expected b to still be cached")
+	}
+}
+
+func TestSignatureCacheConcurrentAccess(t *testing.T) {
+	c := NewSignatureCache(16)
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := []byte{byte(i % 8)}
+			c.Put(key, fakeG1Point{v: big.NewInt(int64(i))})
+			c.Get(key)
+		}()
+	}
+	wg.Wait()
+}
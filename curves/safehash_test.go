@@ -0,0 +1,151 @@
+// Copyright (C) 2018 Authors
+// distributed under Apache 2.0 license
+
+package curves
+
+import (
+	"math/big"
+	"testing"
+)
+
+// fakeCurveOrder is the toy group order backing the CurveSystem test double
+// below. It has no cryptographic meaning; it's just large enough that the
+// hand-picked inputs these tests construct don't collide by accident.
+var fakeCurveOrder = big.NewInt(104729)
+
+// fakeG1Point and fakeG2Point implement Point1 and Point2 as the additive
+// group of integers mod fakeCurveOrder, which is enough arithmetic to
+// exercise HashToG1WithDST/HashToG2WithDST's dangerous-point rejection
+// without a real pairing-friendly curve.
+type fakeG1Point struct{ v *big.Int }
+type fakeG2Point struct{ v *big.Int }
+
+func (p fakeG1Point) Mul(s *big.Int) Point1 {
+	return fakeG1Point{v: new(big.Int).Mod(new(big.Int).Mul(p.v, s), fakeCurveOrder)}
+}
+func (p fakeG1Point) Equals(other Point1) bool    { return p.v.Cmp(other.(fakeG1Point).v) == 0 }
+func (p fakeG1Point) Marshal() []byte             { return p.v.Bytes() }
+func (p fakeG1Point) MarshalUncompressed() []byte { return p.v.Bytes() }
+
+func (p fakeG2Point) Mul(s *big.Int) Point2 {
+	return fakeG2Point{v: new(big.Int).Mod(new(big.Int).Mul(p.v, s), fakeCurveOrder)}
+}
+func (p fakeG2Point) Equals(other Point2) bool    { return p.v.Cmp(other.(fakeG2Point).v) == 0 }
+func (p fakeG2Point) Marshal() []byte             { return p.v.Bytes() }
+func (p fakeG2Point) MarshalUncompressed() []byte { return p.v.Bytes() }
+
+// fakePairResult is the toy pairing output: plain integer multiplication is
+// trivially bilinear, which is all the CurveSystem test double needs to be.
+type fakePairResult struct{ v *big.Int }
+
+func (r fakePairResult) Equals(other PairingResult) bool {
+	return r.v.Cmp(other.(fakePairResult).v) == 0
+}
+
+// fakeCurve is a minimal CurveSystem test double. HashToG1/HashToG2 hash by
+// summing input bytes mod fakeCurveOrder, so a test can force a collision
+// with the identity, generator, or negated generator just by picking bytes
+// that sum to 0, 1, or -1 mod fakeCurveOrder.
+type fakeCurve struct{}
+
+func (fakeCurve) GetG1() Point1        { return fakeG1Point{v: big.NewInt(1)} }
+func (fakeCurve) GetG2() Point2        { return fakeG2Point{v: big.NewInt(1)} }
+func (fakeCurve) GetG1Order() *big.Int { return fakeCurveOrder }
+
+func (fakeCurve) HashToG1(msg []byte) Point1 {
+	return fakeG1Point{v: new(big.Int).Mod(sumBytes(msg), fakeCurveOrder)}
+}
+func (fakeCurve) HashToG2(msg []byte) Point2 {
+	return fakeG2Point{v: new(big.Int).Mod(sumBytes(msg), fakeCurveOrder)}
+}
+
+func (fakeCurve) Pair(a Point1, b Point2) PairingResult {
+	return fakePairResult{v: new(big.Int).Mod(new(big.Int).Mul(a.(fakeG1Point).v, b.(fakeG2Point).v), fakeCurveOrder)}
+}
+func (c fakeCurve) PairingProduct(as []Point1, bs []Point2) PairingResult {
+	sum := big.NewInt(0)
+	for i := range as {
+		sum.Add(sum, c.Pair(as[i], bs[i]).(fakePairResult).v)
+	}
+	return fakePairResult{v: sum.Mod(sum, fakeCurveOrder)}
+}
+
+func (fakeCurve) UnmarshalG1(raw []byte) (Point1, bool) {
+	return fakeG1Point{v: new(big.Int).Mod(new(big.Int).SetBytes(raw), fakeCurveOrder)}, true
+}
+func (fakeCurve) UnmarshalG2(raw []byte) (Point2, bool) {
+	return fakeG2Point{v: new(big.Int).Mod(new(big.Int).SetBytes(raw), fakeCurveOrder)}, true
+}
+
+func sumBytes(b []byte) *big.Int {
+	sum := big.NewInt(0)
+	for _, c := range b {
+		sum.Add(sum, big.NewInt(int64(c)))
+	}
+	return sum
+}
+
+// collisionMsg returns a msg such that frameDST(dst, msg), summed the way
+// fakeCurve's HashToG1/HashToG2 do, equals target mod fakeCurveOrder. It
+// works for any target by spreading the needed sum across as many 0xFF
+// bytes as it takes, so tests can force a specific fake-curve point without
+// hand-computing magic byte values.
+func collisionMsg(dst []byte, target int64) []byte {
+	base := sumBytes(frameDST(dst, nil))
+	need := new(big.Int).Sub(big.NewInt(target), base)
+	need.Mod(need, fakeCurveOrder)
+	var msg []byte
+	n := need.Int64()
+	for n > 255 {
+		msg = append(msg, 0xFF)
+		n -= 255
+	}
+	msg = append(msg, byte(n))
+	return msg
+}
+
+func TestHashToG1WithDSTRejectsIdentity(t *testing.T) {
+	if _, err := HashToG1WithDST(fakeCurve{}, []byte{}, collisionMsg([]byte{}, 0)); err != ErrDangerousPoint {
+		t.Fatalf("expected ErrDangerousPoint for a hash colliding with the identity, got %v", err)
+	}
+}
+
+func TestHashToG1WithDSTRejectsGenerator(t *testing.T) {
+	if _, err := HashToG1WithDST(fakeCurve{}, []byte{}, collisionMsg([]byte{}, 1)); err != ErrDangerousPoint {
+		t.Fatalf("expected ErrDangerousPoint for a hash colliding with the generator, got %v", err)
+	}
+}
+
+func TestHashToG1WithDSTRejectsNegatedGenerator(t *testing.T) {
+	negGen := new(big.Int).Mod(big.NewInt(-1), fakeCurveOrder).Int64()
+	if _, err := HashToG1WithDST(fakeCurve{}, []byte{}, collisionMsg([]byte{}, negGen)); err != ErrDangerousPoint {
+		t.Fatalf("expected ErrDangerousPoint for a hash colliding with the negated generator, got %v", err)
+	}
+}
+
+func TestHashToG1WithDSTAcceptsOrdinaryPoint(t *testing.T) {
+	h, err := HashToG1WithDST(fakeCurve{}, []byte{}, collisionMsg([]byte{}, 5))
+	if err != nil {
+		t.Fatalf("unexpected error for an ordinary hash output: %v", err)
+	}
+	if !h.Equals(fakeG1Point{v: big.NewInt(5)}) {
+		t.Fatalf("got %v, want the point matching the framed byte sum", h)
+	}
+}
+
+func TestFrameDSTSeparatesDomains(t *testing.T) {
+	// Two distinct (dst, msg) pairs with dst of different lengths must not
+	// produce the same framed bytes, even if concatenating dst and msg
+	// naively would.
+	a := frameDST([]byte("A"), []byte("BC"))
+	b := frameDST([]byte("AB"), []byte("C"))
+	if string(a) == string(b) {
+		t.Fatalf("frameDST(%q, %q) collided with frameDST(%q, %q)", "A", "BC", "AB", "C")
+	}
+}
+
+func TestHashToG2WithDSTRejectsGenerator(t *testing.T) {
+	if _, err := HashToG2WithDST(fakeCurve{}, []byte{}, collisionMsg([]byte{}, 1)); err != ErrDangerousPoint {
+		t.Fatalf("expected ErrDangerousPoint for a hash colliding with the generator, got %v", err)
+	}
+}